@@ -0,0 +1,21 @@
+package apple
+
+import "context"
+
+// VerifyAppToken verifies the identityToken a native iOS app sends
+// directly to the backend as part of Sign in with Apple, without going
+// through the authorization-code exchange that Authenticate uses. aud is
+// checked against bundleID and any audiences registered via
+// WithAllowedAudiences, but never against c.ClientID: native clients
+// authenticate as their bundle ID, not the web services ID, and the two
+// must stay distinct even when both belong to the same Team.
+func (c *Client) VerifyAppToken(ctx context.Context, idToken, bundleID string, nonce ...string) (*UserIdentity, error) {
+	n := ""
+	if len(nonce) > 0 {
+		n = nonce[0]
+	}
+
+	allowed := append([]string{bundleID}, c.allowedAudiences...)
+
+	return c.validateToken(idToken, n, allowed)
+}
@@ -0,0 +1,102 @@
+package apple
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, aud string) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss": appleIssuer,
+		"aud": aud,
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+	token.Header["kid"] = "test-kid"
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+
+	return signed
+}
+
+func TestVerifyAppTokenAcceptsBundleIDNotClientID(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	c, err := NewClient(
+		WithTeamID("TEAMID"),
+		WithClientID("web.example.service"),
+		WithKeySet(stubKeySet{key: &key.PublicKey}),
+	)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer c.Close()
+
+	bundleToken := signRS256(t, key, "com.example.app")
+	if _, err := c.VerifyAppToken(context.Background(), bundleToken, "com.example.app"); err != nil {
+		t.Fatalf("VerifyAppToken(bundle id) = %v, want nil", err)
+	}
+
+	webToken := signRS256(t, key, "web.example.service")
+	if _, err := c.VerifyAppToken(context.Background(), webToken, "com.example.app"); err != ErrAudienceMismatch {
+		t.Fatalf("VerifyAppToken(ClientID aud) = %v, want ErrAudienceMismatch", err)
+	}
+}
+
+func TestVerifyAppTokenAcceptsAllowedAudiences(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	c, err := NewClient(
+		WithTeamID("TEAMID"),
+		WithClientID("web.example.service"),
+		WithKeySet(stubKeySet{key: &key.PublicKey}),
+		WithAllowedAudiences("com.example.app.watchos"),
+	)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer c.Close()
+
+	watchToken := signRS256(t, key, "com.example.app.watchos")
+	if _, err := c.VerifyAppToken(context.Background(), watchToken, "com.example.app"); err != nil {
+		t.Fatalf("VerifyAppToken(allowed audience) = %v, want nil", err)
+	}
+}
+
+func TestValidateTokenRejectsBundleID(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	c, err := NewClient(
+		WithTeamID("TEAMID"),
+		WithClientID("web.example.service"),
+		WithKeySet(stubKeySet{key: &key.PublicKey}),
+		WithAllowedAudiences("com.example.app"),
+	)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer c.Close()
+
+	bundleToken := signRS256(t, key, "com.example.app")
+	if err := c.ValidateToken(bundleToken); err != ErrAudienceMismatch {
+		t.Fatalf("ValidateToken(bundle id) = %v, want ErrAudienceMismatch even though it's in AllowedAudiences", err)
+	}
+}
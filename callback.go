@@ -0,0 +1,82 @@
+package apple
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// CallbackResult is the parsed result of an authorization response sent by
+// Apple to the redirect_uri, regardless of the response_mode used to
+// deliver it.
+type CallbackResult struct {
+	State string // The state passed to CreateCallbackURL, echoed back by Apple.
+	Code  string // The authorization code to exchange via Authenticate.
+	Error string // Set instead of Code when the user denied the request or Apple errored.
+
+	// User carries the one-time name payload Apple sends on the user's
+	// very first authorization, only present when the "name" scope was
+	// requested. It is not sent on subsequent logins.
+	User *CallbackUser
+}
+
+// CallbackUser is the one-time name payload Apple includes in the
+// "user" form field on first login.
+// https://developer.apple.com/documentation/sign_in_with_apple/request_an_authorization_to_the_sign_in_with_apple_server
+type CallbackUser struct {
+	Name struct {
+		FirstName string `json:"firstName"`
+		LastName  string `json:"lastName"`
+	} `json:"name"`
+	Email string `json:"email"`
+}
+
+// ParseCallback validates and extracts the authorization response Apple
+// sent to the redirect_uri. It supports the query-string delivery used by
+// ResponseModeQuery and the application/x-www-form-urlencoded POST body
+// used by ResponseModeFormPost.
+//
+// ResponseModeFragment is not, and cannot be, supported here: Apple
+// appends the response after a "#" in that mode, which browsers never
+// send to the server, so no net/http handler can ever observe it. That
+// mode only makes sense for a client-side (JavaScript) redirect_uri.
+func (c *Client) ParseCallback(r *http.Request) (*CallbackResult, error) {
+	var values = r.URL.Query()
+	if r.Method == http.MethodPost {
+		if err := r.ParseForm(); err != nil {
+			return nil, err
+		}
+		values = r.Form
+	}
+
+	state := values.Get("state")
+	if state == "" {
+		return nil, ErrInvalidState
+	}
+
+	if errCode := values.Get("error"); errCode != "" {
+		return &CallbackResult{
+			State: state,
+			Error: errCode,
+		}, nil
+	}
+
+	code := values.Get("code")
+	if code == "" {
+		return nil, ErrInvalidCallback
+	}
+
+	result := CallbackResult{
+		State: state,
+		Code:  code,
+	}
+
+	if raw := values.Get("user"); raw != "" {
+		user := CallbackUser{}
+		if err := json.Unmarshal([]byte(raw), &user); err != nil {
+			return nil, err
+		}
+		result.User = &user
+	}
+
+	return &result, nil
+}
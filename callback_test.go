@@ -0,0 +1,89 @@
+package apple
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestParseCallbackQuery(t *testing.T) {
+	c := &Client{}
+
+	r := httptest.NewRequest(http.MethodGet, "/callback?state=abc&code=the-code", nil)
+
+	result, err := c.ParseCallback(r)
+	if err != nil {
+		t.Fatalf("ParseCallback: %v", err)
+	}
+
+	if result.State != "abc" || result.Code != "the-code" {
+		t.Fatalf("got %+v, want State=abc Code=the-code", result)
+	}
+	if result.Error != "" {
+		t.Fatalf("got Error = %q, want empty", result.Error)
+	}
+}
+
+func TestParseCallbackFormPostWithUser(t *testing.T) {
+	c := &Client{}
+
+	body := url.Values{}
+	body.Set("state", "abc")
+	body.Set("code", "the-code")
+	body.Set("user", `{"name":{"firstName":"Jane","lastName":"Doe"},"email":"jane@example.com"}`)
+
+	r := httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(body.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	result, err := c.ParseCallback(r)
+	if err != nil {
+		t.Fatalf("ParseCallback: %v", err)
+	}
+
+	if result.User == nil {
+		t.Fatal("User = nil, want the decoded payload")
+	}
+	if result.User.Email != "jane@example.com" || result.User.Name.FirstName != "Jane" {
+		t.Fatalf("got User = %+v, want Jane Doe <jane@example.com>", result.User)
+	}
+}
+
+func TestParseCallbackError(t *testing.T) {
+	c := &Client{}
+
+	r := httptest.NewRequest(http.MethodGet, "/callback?state=abc&error=user_cancelled_authorize", nil)
+
+	result, err := c.ParseCallback(r)
+	if err != nil {
+		t.Fatalf("ParseCallback: %v", err)
+	}
+
+	if result.Error != "user_cancelled_authorize" {
+		t.Fatalf("Error = %q, want user_cancelled_authorize", result.Error)
+	}
+	if result.Code != "" {
+		t.Fatalf("Code = %q, want empty when error is set", result.Code)
+	}
+}
+
+func TestParseCallbackMissingState(t *testing.T) {
+	c := &Client{}
+
+	r := httptest.NewRequest(http.MethodGet, "/callback?code=the-code", nil)
+
+	if _, err := c.ParseCallback(r); err != ErrInvalidState {
+		t.Fatalf("ParseCallback error = %v, want ErrInvalidState", err)
+	}
+}
+
+func TestParseCallbackMissingCode(t *testing.T) {
+	c := &Client{}
+
+	r := httptest.NewRequest(http.MethodGet, "/callback?state=abc", nil)
+
+	if _, err := c.ParseCallback(r); err != ErrInvalidCallback {
+		t.Fatalf("ParseCallback error = %v, want ErrInvalidCallback", err)
+	}
+}
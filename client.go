@@ -6,6 +6,7 @@ package apple
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/base64"
@@ -17,15 +18,16 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
-	"sync"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
+	"github.com/golang-jwt/jwt/v5"
 )
 
 const (
 	defaultRequestTimeout = time.Second * 10
 	defaultTokenTTL       = time.Hour
+
+	appleIssuer = "https://appleid.apple.com"
 )
 
 // Config for creating new Client.
@@ -37,15 +39,30 @@ type Config struct {
 
 // Client for interaction with apple-id service.
 type Client struct {
-	TeamID      string      // Your Apple Team ID.
-	ClientID    string      // Your Service which enable sign-in-with-apple service.
-	KeyID       string      // Your Secret Key ID.
-	AESCert     interface{} // Your Secret Key Created By X509 package.
-	RedirectURI string      // Your RedirectURI config in apple website.
-	TokenTTL    int64
-
-	hc   *http.Client
-	keys sync.Map
+	TeamID       string       // Your Apple Team ID.
+	ClientID     string       // Your Service which enable sign-in-with-apple service.
+	KeyID        string       // Your Secret Key ID.
+	RedirectURI  string       // Your RedirectURI config in apple website.
+	TokenTTL     int64
+	ResponseMode ResponseMode // response_mode used for the authorize URL.
+
+	// ECDSAPrivateKey is your Secret Key, as parsed by LoadP8CertByByte /
+	// LoadP8CertByFile, used by the default Signer to sign the
+	// client_secret JWT.
+	ECDSAPrivateKey *ecdsa.PrivateKey
+
+	// AESCert is a misnomer held over from before ECDSAPrivateKey and
+	// the Signer abstraction existed; it is not an AES cert, it is the
+	// same ECDSA private key.
+	//
+	// Deprecated: use ECDSAPrivateKey, or provide your own Signer via
+	// WithSigner.
+	AESCert interface{}
+
+	hc               *http.Client
+	keySet           KeySet
+	allowedAudiences []string
+	signer           Signer
 }
 
 // NewClient returns new client for interaction with apple-id service.
@@ -80,43 +97,24 @@ func NewClient(opts ...ClientOption) (*Client, error) {
 		client.RedirectURI = *settings.RedirectURI
 	}
 
-	return &client, nil
-}
+	client.ResponseMode = settings.ResponseMode
+	client.allowedAudiences = settings.AllowedAudiences
+	client.signer = settings.Signer
 
-// SetPublicKeys gives ability to manually set the public keys.
-func (c *Client) SetPublicKeys(jwkSet *JWKSet) error {
-	c.keys = sync.Map{}
-	for _, k := range jwkSet.Keys {
-		pubKey, err := NewPublicKey(k)
-		if err != nil {
-			return err
-		}
-
-		c.keys.Store(k.KeyID, pubKey)
+	if settings.KeySet != nil {
+		client.keySet = settings.KeySet
+	} else {
+		client.keySet = NewRemoteKeySet(WithKeySetHTTPClient(client.hc))
 	}
+	client.keySet.Start(context.Background())
 
-	return nil
+	return &client, nil
 }
 
-// FetchPublicKeys to verify the ID token signature.
-// https://developer.apple.com/documentation/sign_in_with_apple/fetch_apple_s_public_key_for_verifying_token_signature
-func (c *Client) FetchPublicKeys() (*JWKSet, error) {
-	resp, err := c.hc.Get("https://appleid.apple.com/auth/keys")
-	if err != nil {
-		return nil, fmt.Errorf("%w: %s", ErrRemoveUnavailable, err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return nil, ErrFetchPublicKey
-	}
-
-	var jwkSet JWKSet
-	if err := json.NewDecoder(resp.Body).Decode(&jwkSet); err != nil {
-		return nil, err
-	}
-
-	return &jwkSet, nil
+// Close stops the background refresh started by the client's KeySet. It
+// should be called once the client is no longer needed.
+func (c *Client) Close() {
+	c.keySet.Stop()
 }
 
 // LoadP8CertByByte use x509.ParsePKCS8PrivateKey to Parse cert file.
@@ -127,7 +125,13 @@ func (c *Client) LoadP8CertByByte(data []byte) error {
 		return err
 	}
 
-	c.AESCert = cert
+	key, ok := cert.(*ecdsa.PrivateKey)
+	if !ok {
+		return fmt.Errorf("apple: expected an ECDSA private key, got %T", cert)
+	}
+
+	c.ECDSAPrivateKey = key
+	c.AESCert = cert // Deprecated: kept in sync for callers still reading AESCert directly.
 
 	return nil
 }
@@ -146,6 +150,12 @@ func (c *Client) LoadP8CertByFile(path string) error {
 // state: session ID of the user that Apple will return when
 //        redirect_uri is called so that we can verify the sender.
 func (c *Client) CreateCallbackURL(state string) string {
+	return "https://appleid.apple.com/auth/authorize?" + c.authorizeValues(state).Encode()
+}
+
+// authorizeValues builds the query parameters shared by CreateCallbackURL
+// and CreateAuthRequest.
+func (c *Client) authorizeValues(state string) url.Values {
 	u := url.Values{}
 	u.Add("response_type", "code")
 	u.Add("redirect_uri", c.RedirectURI)
@@ -153,18 +163,23 @@ func (c *Client) CreateCallbackURL(state string) string {
 	u.Add("state", state)
 	u.Add("scope", "name email")
 
-	return "https://appleid.apple.com/auth/authorize?" + u.Encode()
+	if c.ResponseMode != "" {
+		u.Add("response_mode", string(c.ResponseMode))
+	}
+
+	return u
 }
 
-// Authenticate with auth token.
+// Authenticate exchanges an authorization code for tokens.
+// codeVerifier is the CodeVerifier from the AuthRequest that produced
+// authCode; pass "" if the authorization URL was built with
+// CreateCallbackURL instead of CreateAuthRequest. nonce, if given, must
+// match AuthRequest.Nonce and is checked against the id_token's nonce
+// claim.
 // Documentation:
 //   Response: https://developer.apple.com/documentation/sign_in_with_apple/tokenresponse
 //   Error: https://developer.apple.com/documentation/sign_in_with_apple/errorresponse
-func (c *Client) Authenticate(ctx context.Context, authCode string) (*TokenResponse, error) {
-	if c.AESCert == nil {
-		return nil, ErrMissingCert
-	}
-
+func (c *Client) Authenticate(ctx context.Context, authCode, codeVerifier string, nonce ...string) (*TokenResponse, error) {
 	signature, err := c.getSignature()
 	if err != nil {
 		return nil, err
@@ -176,13 +191,16 @@ func (c *Client) Authenticate(ctx context.Context, authCode string) (*TokenRespo
 	v.Set("grant_type", "authorization_code")
 	v.Set("code", authCode)
 	v.Set("redirect_uri", c.RedirectURI)
+	if codeVerifier != "" {
+		v.Set("code_verifier", codeVerifier)
+	}
 
 	token, err := c.doRequest(ctx, v)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := c.ValidateToken(token.IDToken); err != nil {
+	if err := c.ValidateToken(token.IDToken, nonce...); err != nil {
 		return nil, err
 	}
 
@@ -201,10 +219,6 @@ func (c *Client) Authenticate(ctx context.Context, authCode string) (*TokenRespo
 //   Response: https://developer.apple.com/documentation/sign_in_with_apple/tokenresponse
 //   Error: https://developer.apple.com/documentation/sign_in_with_apple/errorresponse
 func (c *Client) Refresh(ctx context.Context, refreshToken string) (*TokenResponse, error) {
-	if c.AESCert == nil {
-		return nil, ErrMissingCert
-	}
-
 	signature, err := c.getSignature()
 	if err != nil {
 		return nil, err
@@ -219,6 +233,50 @@ func (c *Client) Refresh(ctx context.Context, refreshToken string) (*TokenRespon
 	return c.doRequest(ctx, v)
 }
 
+// Revoke invalidates an access or refresh token previously issued by
+// Authenticate or Refresh, e.g. when a user deletes their account.
+// tokenTypeHint is optional and should be "access_token" or
+// "refresh_token" when provided.
+// Documentation: https://developer.apple.com/documentation/sign_in_with_apple/revoke_tokens
+func (c *Client) Revoke(ctx context.Context, token, tokenTypeHint string) error {
+	signature, err := c.getSignature()
+	if err != nil {
+		return err
+	}
+
+	v := url.Values{}
+	v.Set("client_id", c.ClientID)
+	v.Set("client_secret", signature)
+	v.Set("token", token)
+	if tokenTypeHint != "" {
+		v.Set("token_type_hint", tokenTypeHint)
+	}
+
+	body := strings.NewReader(v.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://appleid.apple.com/auth/revoke", body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errResponse := ErrorResponse{}
+		if err := json.NewDecoder(resp.Body).Decode(&errResponse); err != nil {
+			return err
+		}
+
+		return errResponse
+	}
+
+	return nil
+}
+
 func (c *Client) ParseUserIdentity(t string) (*UserIdentity, error) {
 	parts := strings.Split(t, ".")
 	if len(parts) < 2 {
@@ -239,17 +297,70 @@ func (c *Client) ParseUserIdentity(t string) (*UserIdentity, error) {
 	return &userIdentity, nil
 }
 
-func (c *Client) ValidateToken(t string) error {
-	token, err := jwt.Parse(t, c.keyFunc)
+// ValidateToken verifies the signature, issuer, audience and expiry of an
+// id_token issued by Apple through the web/services flow. The audience
+// must be c.ClientID; use VerifyAppToken for native app identityTokens,
+// whose audience is a bundle ID rather than the services ClientID. Pass
+// the expected nonce to also bind the token to the authorization request
+// that produced it; omit it to skip that check.
+func (c *Client) ValidateToken(t string, nonce ...string) error {
+	n := ""
+	if len(nonce) > 0 {
+		n = nonce[0]
+	}
+
+	_, err := c.validateToken(t, n, []string{c.ClientID})
+	return err
+}
+
+// validateToken does the signature, issuer, audience, expiry and
+// (optional) nonce checks shared by ValidateToken and VerifyAppToken, and
+// returns the decoded claims on success. allowedAudiences is the
+// complete, exclusive set of audiences accepted for this call: the two
+// callers intentionally don't share it with each other, so a web
+// id_token (aud == ClientID) can't slip through VerifyAppToken and a
+// native identityToken (aud == bundle ID) can't slip through
+// ValidateToken just because both audiences belong to the same Team.
+func (c *Client) validateToken(t, nonce string, allowedAudiences []string) (*UserIdentity, error) {
+	claims := &UserIdentity{}
+	token, err := jwt.ParseWithClaims(t, claims, c.keyFunc,
+		jwt.WithoutClaimsValidation(),
+	)
 	if err != nil {
-		return err
+		if errors.Is(err, ErrUnexpectedSigningMethod) {
+			return nil, ErrUnexpectedSigningMethod
+		}
+		return nil, err
 	}
 
 	if !token.Valid {
-		return ErrInvalidToken
+		return nil, ErrInvalidToken
 	}
 
-	return nil
+	if claims.Issuer != appleIssuer {
+		return nil, ErrIssuerMismatch
+	}
+
+	aud := false
+	for _, a := range allowedAudiences {
+		if claims.Audience == a {
+			aud = true
+			break
+		}
+	}
+	if !aud {
+		return nil, ErrAudienceMismatch
+	}
+
+	if time.Now().Unix() > claims.ExpiresAt {
+		return nil, ErrTokenExpired
+	}
+
+	if nonce != "" && claims.Nonce != nonce {
+		return nil, ErrNonceMismatch
+	}
+
+	return claims, nil
 }
 
 func (c *Client) doRequest(ctx context.Context, v url.Values) (*TokenResponse, error) {
@@ -282,24 +393,65 @@ func (c *Client) doRequest(ctx context.Context, v url.Values) (*TokenResponse, e
 	return &t, nil
 }
 
+// getSignature builds the client_secret JWT apple-id expects on every
+// token/revoke request, signed by the client's Signer (a local .p8 key by
+// default, or whatever was supplied via WithSigner).
 func (c *Client) getSignature() (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.StandardClaims{
+	signer, err := c.signerOrDefault()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims := clientSecretClaims{
 		Issuer:    c.TeamID,
-		IssuedAt:  time.Now().Unix(),
-		ExpiresAt: time.Now().Unix() + c.TokenTTL,
-		Audience:  "https://appleid.apple.com",
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(time.Duration(c.TokenTTL) * time.Second)),
+		Audience:  appleIssuer,
 		Subject:   c.ClientID,
-	})
+	}
 
-	token.Header = map[string]interface{}{
-		"kid": c.KeyID,
-		"alg": "ES256",
+	return signer.Sign(claims)
+}
+
+// signerOrDefault resolves the Signer used to sign the client_secret: an
+// explicit Signer set via WithSigner, otherwise the default p8Signer
+// built from whichever of ECDSAPrivateKey/AESCert was populated by
+// LoadP8CertByByte/LoadP8CertByFile (or set directly, for callers that
+// predate the Signer abstraction).
+func (c *Client) signerOrDefault() (Signer, error) {
+	if c.signer != nil {
+		return c.signer, nil
 	}
 
-	return token.SignedString(c.AESCert)
+	if c.ECDSAPrivateKey != nil {
+		return &p8Signer{keyID: c.KeyID, key: c.ECDSAPrivateKey}, nil
+	}
+
+	if key, ok := c.AESCert.(*ecdsa.PrivateKey); ok {
+		return &p8Signer{keyID: c.KeyID, key: key}, nil
+	}
+
+	return nil, ErrMissingCert
 }
 
+// keyFunc resolves the public key used to verify t's signature. Apple
+// signs id_tokens with RS256 today but reserves the right to rotate to
+// EC (ES256), so both are accepted here; anything else, including a
+// token that claims an unsigned or symmetric (e.g. HS256) algorithm, is
+// rejected as ErrUnexpectedSigningMethod before the KeySet is ever
+// consulted. This runs instead of jwt.WithValidMethods so that rejection
+// surfaces apple.ErrUnexpectedSigningMethod rather than golang-jwt's own
+// unwrapped internal error. The resolved key's concrete type is checked
+// against the token's alg too, so a JWKS that mixes RSA and EC keys
+// can't be used to smuggle one algorithm's key into the other's slot.
 func (c *Client) keyFunc(t *jwt.Token) (interface{}, error) {
+	switch t.Method.Alg() {
+	case jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg():
+	default:
+		return nil, ErrUnexpectedSigningMethod
+	}
+
 	rawKid, ok := t.Header["kid"]
 	if !ok {
 		return nil, errors.New("jwt: kid not found")
@@ -310,25 +462,21 @@ func (c *Client) keyFunc(t *jwt.Token) (interface{}, error) {
 		return nil, errors.New("jwt: wrong kid")
 	}
 
-	v, ok := c.keys.Load(kid)
-	if ok {
-		return v.(*rsa.PublicKey), nil
-	}
-
-	// Fetch and update public keys if it does not exist.
-	jwkSet, err := c.FetchPublicKeys()
+	key, err := c.keySet.Key(context.Background(), kid)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := c.SetPublicKeys(jwkSet); err != nil {
-		return nil, err
-	}
-
-	v, ok = c.keys.Load(kid)
-	if ok {
-		return v.(*rsa.PublicKey), nil
+	switch t.Method.Alg() {
+	case jwt.SigningMethodRS256.Alg():
+		if _, ok := key.(*rsa.PublicKey); !ok {
+			return nil, ErrUnexpectedSigningMethod
+		}
+	case jwt.SigningMethodES256.Alg():
+		if _, ok := key.(*ecdsa.PublicKey); !ok {
+			return nil, ErrUnexpectedSigningMethod
+		}
 	}
 
-	return nil, ErrInvalidToken
+	return key, nil
 }
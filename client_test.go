@@ -0,0 +1,95 @@
+package apple
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// stubKeySet is a KeySet that always resolves to the same key, for tests
+// that don't care about fetching/rotation.
+type stubKeySet struct {
+	key crypto.PublicKey
+}
+
+func (ks stubKeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	return ks.key, nil
+}
+
+func (ks stubKeySet) Start(ctx context.Context) {}
+func (ks stubKeySet) Stop()                     {}
+
+func TestValidateTokenRejectsUnexpectedSigningMethod(t *testing.T) {
+	c, err := NewClient(
+		WithTeamID("TEAMID"),
+		WithClientID("CLIENTID"),
+		WithKeySet(stubKeySet{}),
+	)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer c.Close()
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"iss": appleIssuer,
+		"aud": "CLIENTID",
+		"exp": now.Add(time.Hour).Unix(),
+	})
+	token.Header["kid"] = "whatever"
+
+	signed, err := token.SignedString([]byte("not-apples-key"))
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+
+	err = c.ValidateToken(signed)
+	if !errors.Is(err, ErrUnexpectedSigningMethod) {
+		t.Fatalf("ValidateToken error = %v, want ErrUnexpectedSigningMethod", err)
+	}
+}
+
+// TestValidateTokenAcceptsES256 proves that an EC key resolved by the
+// KeySet is actually usable for verification, not just parseable by
+// NewPublicKey: a token signed with ES256 and a matching kid must
+// validate end-to-end.
+func TestValidateTokenAcceptsES256(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	c, err := NewClient(
+		WithTeamID("TEAMID"),
+		WithClientID("CLIENTID"),
+		WithKeySet(stubKeySet{key: &ecKey.PublicKey}),
+	)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer c.Close()
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": appleIssuer,
+		"aud": "CLIENTID",
+		"exp": now.Add(time.Hour).Unix(),
+	})
+	token.Header["kid"] = "ec-key"
+
+	signed, err := token.SignedString(ecKey)
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+
+	if err := c.ValidateToken(signed); err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+}
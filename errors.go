@@ -0,0 +1,20 @@
+package apple
+
+import "errors"
+
+// Errors returned while talking to the apple-id service or while
+// validating tokens issued by it.
+var (
+	ErrMissingCert       = errors.New("apple: missing certificate")
+	ErrInvalidToken      = errors.New("apple: invalid token")
+	ErrFetchPublicKey    = errors.New("apple: failed to fetch public key")
+	ErrRemoveUnavailable = errors.New("apple: remote service unavailable")
+	ErrInvalidState      = errors.New("apple: invalid callback state")
+	ErrInvalidCallback   = errors.New("apple: invalid callback payload")
+
+	ErrTokenExpired            = errors.New("apple: token is expired")
+	ErrIssuerMismatch          = errors.New("apple: unexpected issuer")
+	ErrAudienceMismatch        = errors.New("apple: unexpected audience")
+	ErrNonceMismatch           = errors.New("apple: unexpected nonce")
+	ErrUnexpectedSigningMethod = errors.New("apple: unexpected signing method")
+)
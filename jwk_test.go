@@ -0,0 +1,50 @@
+package apple
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"testing"
+)
+
+func TestNewPublicKeyRSA(t *testing.T) {
+	key, err := NewPublicKey(JWK{
+		KeyType: "RSA",
+		KeyID:   "test-rsa",
+		N:       "wteSUVe2iGFpbwL2SPG9GyTG8XG8Ak1qTS8esGJ-MHw",
+		E:       "AQAB",
+	})
+	if err != nil {
+		t.Fatalf("NewPublicKey: %v", err)
+	}
+
+	if _, ok := key.(*rsa.PublicKey); !ok {
+		t.Fatalf("got %T, want *rsa.PublicKey", key)
+	}
+}
+
+func TestNewPublicKeyEC(t *testing.T) {
+	key, err := NewPublicKey(JWK{
+		KeyType: "EC",
+		KeyID:   "test-ec",
+		Curve:   "P-256",
+		X:       "MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4",
+		Y:       "4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFGM",
+	})
+	if err != nil {
+		t.Fatalf("NewPublicKey: %v", err)
+	}
+
+	if _, ok := key.(*ecdsa.PublicKey); !ok {
+		t.Fatalf("got %T, want *ecdsa.PublicKey", key)
+	}
+}
+
+func TestNewPublicKeyRejectsIncompleteOrUnsupportedJWK(t *testing.T) {
+	if _, err := NewPublicKey(JWK{KeyType: "EC", KeyID: "no-x-y"}); err == nil {
+		t.Fatal("expected an error for an EC JWK missing x/y, got nil")
+	}
+
+	if _, err := NewPublicKey(JWK{KeyType: "oct", KeyID: "oct-key"}); err == nil {
+		t.Fatal("expected an error for an unsupported kty, got nil")
+	}
+}
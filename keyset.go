@@ -0,0 +1,272 @@
+package apple
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	applePublicKeysURL = "https://appleid.apple.com/auth/keys"
+
+	defaultKeySetRefreshInterval = time.Hour
+	defaultKeySetGracePeriod     = 24 * time.Hour
+)
+
+// KeySet resolves the public key Apple used to sign an id_token. It is
+// modeled on the key manager used by go-oidc: implementations are
+// expected to cache keys and refresh them in the background rather than
+// hitting the network on every lookup.
+type KeySet interface {
+	// Key returns the public key for the given kid.
+	Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+
+	// Start begins whatever background refresh work the KeySet needs.
+	// It must be safe to call on a KeySet that does no background work.
+	Start(ctx context.Context)
+
+	// Stop releases resources started by Start.
+	Stop()
+}
+
+// RemoteKeySet is the default KeySet. It fetches
+// https://appleid.apple.com/auth/keys, honours Cache-Control/Expires on
+// the response to decide when to refresh next, coalesces concurrent
+// refreshes via singleflight, and keeps the previous generation of keys
+// around for gracePeriod so that in-flight tokens survive a rotation.
+type RemoteKeySet struct {
+	hc              *http.Client
+	url             string
+	refreshInterval time.Duration
+	gracePeriod     time.Duration
+
+	sf singleflight.Group
+
+	mu            sync.RWMutex
+	current       map[string]crypto.PublicKey
+	previous      map[string]crypto.PublicKey
+	previousSetAt time.Time
+	expiresAt     time.Time
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// RemoteKeySetOption configures a RemoteKeySet created by NewRemoteKeySet.
+type RemoteKeySetOption func(*RemoteKeySet)
+
+// WithKeySetHTTPClient overrides the HTTP client used to fetch keys.
+func WithKeySetHTTPClient(hc *http.Client) RemoteKeySetOption {
+	return func(ks *RemoteKeySet) {
+		ks.hc = hc
+	}
+}
+
+// WithKeySetRefreshInterval overrides the upper bound on how long the
+// RemoteKeySet waits between refreshes when the response carries no
+// caching headers.
+func WithKeySetRefreshInterval(d time.Duration) RemoteKeySetOption {
+	return func(ks *RemoteKeySet) {
+		ks.refreshInterval = d
+	}
+}
+
+// WithKeySetGracePeriod overrides how long keys from the previous
+// generation remain valid after being superseded by a refresh.
+func WithKeySetGracePeriod(d time.Duration) RemoteKeySetOption {
+	return func(ks *RemoteKeySet) {
+		ks.gracePeriod = d
+	}
+}
+
+// NewRemoteKeySet returns a RemoteKeySet ready to be started with Start.
+func NewRemoteKeySet(opts ...RemoteKeySetOption) *RemoteKeySet {
+	ks := &RemoteKeySet{
+		hc:              &http.Client{Timeout: defaultRequestTimeout},
+		url:             applePublicKeysURL,
+		refreshInterval: defaultKeySetRefreshInterval,
+		gracePeriod:     defaultKeySetGracePeriod,
+	}
+
+	for _, opt := range opts {
+		opt(ks)
+	}
+
+	return ks
+}
+
+// Key implements KeySet.
+func (ks *RemoteKeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	if key, ok := ks.lookup(kid); ok {
+		return key, nil
+	}
+
+	// Coalesce concurrent lookups for an unknown kid into a single
+	// refresh instead of stampeding the network.
+	if _, err, _ := ks.sf.Do("refresh", func() (interface{}, error) {
+		return nil, ks.refresh(ctx)
+	}); err != nil {
+		return nil, err
+	}
+
+	if key, ok := ks.lookup(kid); ok {
+		return key, nil
+	}
+
+	return nil, ErrInvalidToken
+}
+
+func (ks *RemoteKeySet) lookup(kid string) (crypto.PublicKey, bool) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	if key, ok := ks.current[kid]; ok {
+		return key, true
+	}
+
+	// Keys from the previous generation are honoured for gracePeriod after
+	// being superseded, so that tokens signed just before a rotation
+	// still verify.
+	if time.Since(ks.previousSetAt) <= ks.gracePeriod {
+		if key, ok := ks.previous[kid]; ok {
+			return key, true
+		}
+	}
+
+	return nil, false
+}
+
+func (ks *RemoteKeySet) refresh(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ks.url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := ks.hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrRemoveUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ErrFetchPublicKey
+	}
+
+	var jwkSet JWKSet
+	if err := json.NewDecoder(resp.Body).Decode(&jwkSet); err != nil {
+		return err
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(jwkSet.Keys))
+	for _, k := range jwkSet.Keys {
+		pubKey, err := NewPublicKey(k)
+		if err != nil {
+			return err
+		}
+
+		keys[k.KeyID] = pubKey
+	}
+
+	ks.mu.Lock()
+	// Only a changed key set starts a new generation; re-fetching the same
+	// keys must not push the previous generation out before gracePeriod.
+	rotated := len(keys) != len(ks.current)
+	for kid := range ks.current {
+		if _, ok := keys[kid]; !ok {
+			rotated = true
+			break
+		}
+	}
+	if rotated {
+		ks.previous = ks.current
+		ks.previousSetAt = time.Now()
+	}
+	ks.current = keys
+	ks.expiresAt = cacheExpiry(resp.Header, ks.refreshInterval)
+	ks.mu.Unlock()
+
+	return nil
+}
+
+// Start implements KeySet: it refreshes immediately in the background
+// and keeps refreshing on the cadence learned from Cache-Control/Expires,
+// capped by refreshInterval.
+func (ks *RemoteKeySet) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	ks.cancel = cancel
+	ks.done = make(chan struct{})
+
+	go ks.refreshLoop(ctx)
+}
+
+// Stop implements KeySet.
+func (ks *RemoteKeySet) Stop() {
+	if ks.cancel == nil {
+		return
+	}
+
+	ks.cancel()
+	<-ks.done
+}
+
+func (ks *RemoteKeySet) refreshLoop(ctx context.Context) {
+	defer close(ks.done)
+
+	_ = ks.refresh(ctx)
+
+	for {
+		wait := ks.refreshInterval
+
+		ks.mu.RLock()
+		if !ks.expiresAt.IsZero() {
+			if d := time.Until(ks.expiresAt); d > 0 && d < wait {
+				wait = d
+			}
+		}
+		ks.mu.RUnlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			_ = ks.refresh(ctx)
+		}
+	}
+}
+
+// cacheExpiry figures out when the fetched keys should be considered
+// stale, preferring Cache-Control's max-age over Expires, and falling
+// back to the caller-supplied default when neither is present.
+func cacheExpiry(h http.Header, fallback time.Duration) time.Time {
+	now := time.Now()
+
+	if cc := h.Get("Cache-Control"); cc != "" {
+		for _, part := range strings.Split(cc, ",") {
+			part = strings.TrimSpace(part)
+			if strings.HasPrefix(part, "max-age=") {
+				secs := strings.TrimPrefix(part, "max-age=")
+				if n, err := strconv.Atoi(secs); err == nil {
+					return now.Add(time.Duration(n) * time.Second)
+				}
+			}
+		}
+	}
+
+	if exp := h.Get("Expires"); exp != "" {
+		if t, err := http.ParseTime(exp); err == nil {
+			return t
+		}
+	}
+
+	return now.Add(fallback)
+}
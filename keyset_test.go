@@ -0,0 +1,101 @@
+package apple
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCacheExpiryPrefersCacheControlMaxAge(t *testing.T) {
+	h := http.Header{}
+	h.Set("Cache-Control", "public, max-age=3600")
+	h.Set("Expires", time.Now().Add(48*time.Hour).UTC().Format(http.TimeFormat))
+
+	got := cacheExpiry(h, time.Minute)
+	want := time.Now().Add(time.Hour)
+
+	if diff := got.Sub(want); diff < -time.Second || diff > time.Second {
+		t.Fatalf("cacheExpiry = %v, want ~%v", got, want)
+	}
+}
+
+func TestCacheExpiryFallsBackToExpiresHeader(t *testing.T) {
+	exp := time.Now().Add(2 * time.Hour).Truncate(time.Second).UTC()
+
+	h := http.Header{}
+	h.Set("Expires", exp.Format(http.TimeFormat))
+
+	got := cacheExpiry(h, time.Minute)
+	if !got.Equal(exp) {
+		t.Fatalf("cacheExpiry = %v, want %v", got, exp)
+	}
+}
+
+func TestCacheExpiryFallsBackToDefault(t *testing.T) {
+	got := cacheExpiry(http.Header{}, time.Hour)
+	want := time.Now().Add(time.Hour)
+
+	if diff := got.Sub(want); diff < -time.Second || diff > time.Second {
+		t.Fatalf("cacheExpiry = %v, want ~%v", got, want)
+	}
+}
+
+func TestRemoteKeySetLookupHonoursGracePeriod(t *testing.T) {
+	ks := &RemoteKeySet{
+		current:     map[string]crypto.PublicKey{},
+		previous:    map[string]crypto.PublicKey{"old-kid": &rsa.PublicKey{}},
+		gracePeriod: time.Minute,
+	}
+
+	ks.previousSetAt = time.Now()
+	if _, ok := ks.lookup("old-kid"); !ok {
+		t.Fatal("lookup should find a previous-generation key within gracePeriod")
+	}
+
+	ks.previousSetAt = time.Now().Add(-2 * time.Minute)
+	if _, ok := ks.lookup("old-kid"); ok {
+		t.Fatal("lookup should not find a previous-generation key past gracePeriod")
+	}
+}
+
+func TestRemoteKeySetKeyFetchesAndCaches(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"keys":[{"kty":"RSA","kid":"test-rsa","n":"wteSUVe2iGFpbwL2SPG9GyTG8XG8Ak1qTS8esGJ-MHw","e":"AQAB"}]}`))
+	}))
+	defer srv.Close()
+
+	ks := NewRemoteKeySet(WithKeySetHTTPClient(srv.Client()))
+	ks.url = srv.URL
+
+	key, err := ks.Key(context.Background(), "test-rsa")
+	if err != nil {
+		t.Fatalf("Key: %v", err)
+	}
+	if _, ok := key.(*rsa.PublicKey); !ok {
+		t.Fatalf("got %T, want *rsa.PublicKey", key)
+	}
+	if hits != 1 {
+		t.Fatalf("server hits = %d, want 1", hits)
+	}
+
+	if _, err := ks.Key(context.Background(), "test-rsa"); err != nil {
+		t.Fatalf("Key (cached): %v", err)
+	}
+	if hits != 1 {
+		t.Fatalf("server hits after cached lookup = %d, want 1", hits)
+	}
+
+	if _, err := ks.Key(context.Background(), "unknown-kid"); err == nil {
+		t.Fatal("expected an error for an unknown kid")
+	}
+	if hits != 2 {
+		t.Fatalf("server hits after unknown-kid lookup = %d, want 2 (one refresh attempt)", hits)
+	}
+}
@@ -0,0 +1,118 @@
+package apple
+
+import "net/http"
+
+// ResponseMode controls how Apple delivers the authorization response to
+// the redirect URI.
+// https://developer.apple.com/documentation/sign_in_with_apple/request_an_authorization_to_the_sign_in_with_apple_server
+type ResponseMode string
+
+const (
+	ResponseModeQuery    ResponseMode = "query"
+	ResponseModeFragment ResponseMode = "fragment"
+	ResponseModeFormPost ResponseMode = "form_post"
+)
+
+// ClientSettings are the settings collected from ClientOption and applied
+// to a new Client in NewClient.
+type ClientSettings struct {
+	TeamID           string
+	ClientID         string
+	KeyID            string
+	HTTPClient       *http.Client
+	TokenTTL         *int64
+	RedirectURI      *string
+	ResponseMode     ResponseMode
+	KeySet           KeySet
+	AllowedAudiences []string
+	Signer           Signer
+}
+
+// ClientOption configures a Client created by NewClient.
+type ClientOption interface {
+	Apply(*ClientSettings)
+}
+
+type clientOptionFunc func(*ClientSettings)
+
+func (f clientOptionFunc) Apply(s *ClientSettings) {
+	f(s)
+}
+
+// WithTeamID sets the Apple Team ID.
+func WithTeamID(teamID string) ClientOption {
+	return clientOptionFunc(func(s *ClientSettings) {
+		s.TeamID = teamID
+	})
+}
+
+// WithClientID sets the Services ID that has Sign in with Apple enabled.
+func WithClientID(clientID string) ClientOption {
+	return clientOptionFunc(func(s *ClientSettings) {
+		s.ClientID = clientID
+	})
+}
+
+// WithKeyID sets the Secret Key ID obtained from the Apple Developer Account.
+func WithKeyID(keyID string) ClientOption {
+	return clientOptionFunc(func(s *ClientSettings) {
+		s.KeyID = keyID
+	})
+}
+
+// WithHTTPClient overrides the default HTTP client used to talk to Apple.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return clientOptionFunc(func(s *ClientSettings) {
+		s.HTTPClient = hc
+	})
+}
+
+// WithTokenTTL overrides the default lifetime, in seconds, of the
+// client_secret generated for each request.
+func WithTokenTTL(ttl int64) ClientOption {
+	return clientOptionFunc(func(s *ClientSettings) {
+		s.TokenTTL = &ttl
+	})
+}
+
+// WithRedirectURI sets the redirect_uri configured on the apple website.
+func WithRedirectURI(uri string) ClientOption {
+	return clientOptionFunc(func(s *ClientSettings) {
+		s.RedirectURI = &uri
+	})
+}
+
+// WithResponseMode sets the response_mode used for the authorization
+// request. Defaults to ResponseModeQuery. Apple requires
+// ResponseModeFormPost whenever the "name email" scope is requested.
+func WithResponseMode(mode ResponseMode) ClientOption {
+	return clientOptionFunc(func(s *ClientSettings) {
+		s.ResponseMode = mode
+	})
+}
+
+// WithKeySet overrides the default RemoteKeySet used to resolve Apple's
+// public keys, e.g. with a file-backed or Redis-backed KeySet.
+func WithKeySet(ks KeySet) ClientOption {
+	return clientOptionFunc(func(s *ClientSettings) {
+		s.KeySet = ks
+	})
+}
+
+// WithSigner overrides how the client_secret JWT is signed, e.g. with a
+// Signer backed by a KMS or HSM instead of an in-memory .p8 key.
+func WithSigner(signer Signer) ClientOption {
+	return clientOptionFunc(func(s *ClientSettings) {
+		s.Signer = signer
+	})
+}
+
+// WithAllowedAudiences registers additional audiences accepted by
+// VerifyAppToken alongside the bundleID passed to each call, e.g. other
+// iOS bundle IDs that share the Team ID with this services ID. It has no
+// effect on ValidateToken/Authenticate, which only ever accept ClientID.
+func WithAllowedAudiences(audiences ...string) ClientOption {
+	return clientOptionFunc(func(s *ClientSettings) {
+		s.AllowedAudiences = append(s.AllowedAudiences, audiences...)
+	})
+}
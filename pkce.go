@@ -0,0 +1,58 @@
+package apple
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// AuthRequest is the result of CreateAuthRequest: the authorize URL to
+// send the user to, and the PKCE/nonce values the caller must hold onto
+// (typically in the user's session) to complete the flow in Authenticate.
+type AuthRequest struct {
+	URL          string
+	State        string
+	Nonce        string
+	CodeVerifier string
+}
+
+// CreateAuthRequest is CreateCallbackURL plus PKCE (RFC 7636) and a
+// nonce: it generates a code_verifier/code_challenge pair and a random
+// nonce, and returns them alongside the authorize URL so the caller can
+// pass CodeVerifier and Nonce to Authenticate once Apple calls back.
+// PKCE defends against authorization code interception; the nonce binds
+// the id_token to this specific authorization request.
+func (c *Client) CreateAuthRequest(state string) (*AuthRequest, error) {
+	codeVerifier, err := randomURLSafeString(32)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := randomURLSafeString(16)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := sha256.Sum256([]byte(codeVerifier))
+
+	v := c.authorizeValues(state)
+	v.Set("nonce", nonce)
+	v.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	v.Set("code_challenge_method", "S256")
+
+	return &AuthRequest{
+		URL:          "https://appleid.apple.com/auth/authorize?" + v.Encode(),
+		State:        state,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+	}, nil
+}
+
+func randomURLSafeString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,69 @@
+package apple
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestCreateAuthRequest(t *testing.T) {
+	c := &Client{
+		ClientID:    "CLIENTID",
+		RedirectURI: "https://example.com/callback",
+	}
+
+	req, err := c.CreateAuthRequest("the-state")
+	if err != nil {
+		t.Fatalf("CreateAuthRequest: %v", err)
+	}
+
+	if req.State != "the-state" {
+		t.Fatalf("State = %q, want the-state", req.State)
+	}
+	if req.CodeVerifier == "" || req.Nonce == "" {
+		t.Fatalf("got empty CodeVerifier/Nonce: %+v", req)
+	}
+
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		t.Fatalf("url.Parse(req.URL): %v", err)
+	}
+	q := u.Query()
+
+	if q.Get("nonce") != req.Nonce {
+		t.Fatalf("authorize URL nonce = %q, want %q", q.Get("nonce"), req.Nonce)
+	}
+	if q.Get("code_challenge_method") != "S256" {
+		t.Fatalf("code_challenge_method = %q, want S256", q.Get("code_challenge_method"))
+	}
+
+	wantChallenge := sha256.Sum256([]byte(req.CodeVerifier))
+	if q.Get("code_challenge") != base64.RawURLEncoding.EncodeToString(wantChallenge[:]) {
+		t.Fatal("code_challenge does not match sha256(code_verifier)")
+	}
+}
+
+func TestCreateAuthRequestUniqueValues(t *testing.T) {
+	c := &Client{ClientID: "CLIENTID", RedirectURI: "https://example.com/callback"}
+
+	a, err := c.CreateAuthRequest("state")
+	if err != nil {
+		t.Fatalf("CreateAuthRequest: %v", err)
+	}
+	b, err := c.CreateAuthRequest("state")
+	if err != nil {
+		t.Fatalf("CreateAuthRequest: %v", err)
+	}
+
+	if a.CodeVerifier == b.CodeVerifier {
+		t.Fatal("two calls produced the same CodeVerifier")
+	}
+	if a.Nonce == b.Nonce {
+		t.Fatal("two calls produced the same Nonce")
+	}
+	if !strings.Contains(a.URL, "client_id=CLIENTID") {
+		t.Fatalf("authorize URL = %q, want it to include client_id", a.URL)
+	}
+}
@@ -0,0 +1,68 @@
+package apple
+
+import (
+	"crypto/ecdsa"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Signer produces the signed client_secret JWT Apple expects on
+// token/revoke requests. The default, p8Signer, signs locally with an
+// ECDSA private key loaded from Apple's .p8 file; implement Signer
+// yourself to sign with AWS KMS, Google Cloud KMS, Azure Key Vault, or a
+// PKCS#11 HSM instead of keeping that key on disk.
+type Signer interface {
+	Sign(claims jwt.Claims) (string, error)
+}
+
+// p8Signer is the default Signer, used whenever no Signer is supplied via
+// WithSigner.
+type p8Signer struct {
+	keyID string
+	key   *ecdsa.PrivateKey
+}
+
+func (s *p8Signer) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["kid"] = s.keyID
+
+	return token.SignedString(s.key)
+}
+
+// clientSecretClaims are the claims of the client_secret JWT Apple
+// expects on token/revoke requests. It deliberately declares Audience as
+// a plain string rather than using jwt.RegisteredClaims: RegisteredClaims
+// embeds jwt.ClaimStrings, which golang-jwt/v5 marshals as a JSON array
+// by default ("aud":["..."]) instead of the scalar
+// ("aud":"https://appleid.apple.com") Apple's endpoint expects.
+type clientSecretClaims struct {
+	Issuer    string           `json:"iss"`
+	IssuedAt  *jwt.NumericDate `json:"iat,omitempty"`
+	ExpiresAt *jwt.NumericDate `json:"exp,omitempty"`
+	Audience  string           `json:"aud"`
+	Subject   string           `json:"sub"`
+}
+
+func (c clientSecretClaims) GetExpirationTime() (*jwt.NumericDate, error) {
+	return c.ExpiresAt, nil
+}
+
+func (c clientSecretClaims) GetIssuedAt() (*jwt.NumericDate, error) {
+	return c.IssuedAt, nil
+}
+
+func (c clientSecretClaims) GetNotBefore() (*jwt.NumericDate, error) {
+	return nil, nil
+}
+
+func (c clientSecretClaims) GetIssuer() (string, error) {
+	return c.Issuer, nil
+}
+
+func (c clientSecretClaims) GetSubject() (string, error) {
+	return c.Subject, nil
+}
+
+func (c clientSecretClaims) GetAudience() (jwt.ClaimStrings, error) {
+	return jwt.ClaimStrings{c.Audience}, nil
+}
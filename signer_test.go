@@ -0,0 +1,30 @@
+package apple
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestClientSecretClaimsAudienceIsScalar(t *testing.T) {
+	now := jwt.NewNumericDate(time.Unix(1000, 0))
+	claims := clientSecretClaims{
+		Issuer:    "TEAMID",
+		IssuedAt:  now,
+		ExpiresAt: now,
+		Audience:  appleIssuer,
+		Subject:   "CLIENTID",
+	}
+
+	b, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+
+	if want := `"aud":"https://appleid.apple.com"`; !strings.Contains(string(b), want) {
+		t.Fatalf("client_secret claims = %s, want substring %s", b, want)
+	}
+}
@@ -0,0 +1,92 @@
+package apple
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TokenResponse is returned by the token endpoint.
+// https://developer.apple.com/documentation/sign_in_with_apple/tokenresponse
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	IDToken      string `json:"id_token"`
+
+	UserIdentity UserIdentity `json:"-"`
+}
+
+// ErrorResponse is returned by apple-id endpoints on failure.
+// https://developer.apple.com/documentation/sign_in_with_apple/errorresponse
+type ErrorResponse struct {
+	Code string `json:"error"`
+}
+
+func (e ErrorResponse) Error() string {
+	return e.Code
+}
+
+// UserIdentity is the set of claims carried by the id_token.
+type UserIdentity struct {
+	ID             string `json:"sub"`
+	Email          string `json:"email"`
+	EmailVerified  bool   `json:"email_verified,string"`
+	IsPrivateEmail bool   `json:"is_private_email,string"`
+	Issuer         string `json:"iss"`
+	Audience       string `json:"aud"`
+	ExpiresAt      int64  `json:"exp"`
+	IssuedAt       int64  `json:"iat"`
+	Nonce          string `json:"nonce"`
+}
+
+// The Get* methods below satisfy jwt.Claims (github.com/golang-jwt/jwt/v5)
+// so that UserIdentity can be passed to jwt.ParseWithClaims directly.
+// validateToken runs its own issuer/audience/expiry/nonce checks against
+// the plain fields above and returns caller-distinguishable errors, so
+// parsing is done with jwt.WithoutClaimsValidation() and these are never
+// consulted by the library itself.
+
+func (u UserIdentity) GetExpirationTime() (*jwt.NumericDate, error) {
+	return jwt.NewNumericDate(time.Unix(u.ExpiresAt, 0)), nil
+}
+
+func (u UserIdentity) GetIssuedAt() (*jwt.NumericDate, error) {
+	return jwt.NewNumericDate(time.Unix(u.IssuedAt, 0)), nil
+}
+
+func (u UserIdentity) GetNotBefore() (*jwt.NumericDate, error) {
+	return nil, nil
+}
+
+func (u UserIdentity) GetIssuer() (string, error) {
+	return u.Issuer, nil
+}
+
+func (u UserIdentity) GetSubject() (string, error) {
+	return u.ID, nil
+}
+
+func (u UserIdentity) GetAudience() (jwt.ClaimStrings, error) {
+	return jwt.ClaimStrings{u.Audience}, nil
+}
+
+// JWKSet is the set of keys published at https://appleid.apple.com/auth/keys.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK is a single JSON Web Key as published by Apple. N/E are populated
+// for kty "RSA"; Curve/X/Y are populated for kty "EC".
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n"`
+	E         string `json:"e"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	Y         string `json:"y"`
+}